@@ -24,8 +24,10 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
 	"knative.dev/pkg/test/logging"
 	v1 "knative.dev/serving/pkg/apis/serving/v1"
 	"knative.dev/serving/pkg/apis/serving/v1alpha1"
@@ -33,18 +35,30 @@ import (
 	pkgTest "knative.dev/pkg/test"
 	v1alpha1testing "knative.dev/serving/pkg/testing/v1alpha1"
 	"knative.dev/serving/test"
+	"knative.dev/serving/test/v1alpha1/internal/watcher"
 )
 
 // CreateConfiguration create a configuration resource in namespace with the name names.Config
 // that uses the image specified by names.Image.
 func CreateConfiguration(t pkgTest.T, clients *test.Clients, names test.ResourceNames, fopt ...v1alpha1testing.ConfigOption) (*v1alpha1.Configuration, error) {
-	config := Configuration(names, fopt...)
+	config, err := Configuration(names, fopt...)
+	if err != nil {
+		return nil, err
+	}
 	LogResourceObject(t, ResourceObjects{Config: config})
 	return clients.ServingAlphaClient.Configs.Create(config)
 }
 
 // PatchConfigImage patches the existing config passed in with a new imagePath. Returns the latest Configuration object
-func PatchConfigImage(clients *test.Clients, cfg *v1alpha1.Configuration, imagePath string) (*v1alpha1.Configuration, error) {
+func PatchConfigImage(clients *test.Clients, cfg *v1alpha1.Configuration, imagePath string, iopt ...ImageOption) (*v1alpha1.Configuration, error) {
+	newRef, err := normalizeImage(imagePath, iopt...)
+	if err != nil {
+		return nil, err
+	}
+	if oldRef, err := normalizeImage(cfg.Spec.GetTemplate().Spec.GetContainer().Image); err == nil && oldRef.Name() == newRef.Name() {
+		return nil, &NoOpPatchError{Image: imagePath}
+	}
+
 	newCfg := cfg.DeepCopy()
 	newCfg.Spec.GetTemplate().Spec.GetContainer().Image = imagePath
 	patchBytes, err := test.CreateBytePatch(cfg, newCfg)
@@ -77,8 +91,11 @@ func WaitForConfigLatestRevision(clients *test.Clients, names test.ResourceNames
 }
 
 // ConfigurationSpec returns the spec of a configuration to be used throughout different
-// CRD helpers.
-func ConfigurationSpec(imagePath string) *v1alpha1.ConfigurationSpec {
+// CRD helpers. It returns an error if imagePath is not a valid image reference.
+func ConfigurationSpec(imagePath string, iopt ...ImageOption) (*v1alpha1.ConfigurationSpec, error) {
+	if _, err := normalizeImage(imagePath, iopt...); err != nil {
+		return nil, err
+	}
 	return &v1alpha1.ConfigurationSpec{
 		Template: &v1alpha1.RevisionTemplateSpec{
 			Spec: v1alpha1.RevisionSpec{
@@ -91,12 +108,15 @@ func ConfigurationSpec(imagePath string) *v1alpha1.ConfigurationSpec {
 				},
 			},
 		},
-	}
+	}, nil
 }
 
 // LegacyConfigurationSpec returns the spec of a configuration to be used throughout different
-// CRD helpers.
-func LegacyConfigurationSpec(imagePath string) *v1alpha1.ConfigurationSpec {
+// CRD helpers. It returns an error if imagePath is not a valid image reference.
+func LegacyConfigurationSpec(imagePath string, iopt ...ImageOption) (*v1alpha1.ConfigurationSpec, error) {
+	if _, err := normalizeImage(imagePath, iopt...); err != nil {
+		return nil, err
+	}
 	return &v1alpha1.ConfigurationSpec{
 		DeprecatedRevisionTemplate: &v1alpha1.RevisionTemplateSpec{
 			Spec: v1alpha1.RevisionSpec{
@@ -106,24 +126,30 @@ func LegacyConfigurationSpec(imagePath string) *v1alpha1.ConfigurationSpec {
 				RevisionSpec: v1.RevisionSpec{},
 			},
 		},
-	}
+	}, nil
 }
 
 // Configuration returns a Configuration object in namespace with the name names.Config
-// that uses the image specified by names.Image
-func Configuration(names test.ResourceNames, fopt ...v1alpha1testing.ConfigOption) *v1alpha1.Configuration {
+// that uses the image specified by names.Image. It returns an error if that image does
+// not parse as a valid image reference.
+func Configuration(names test.ResourceNames, fopt ...v1alpha1testing.ConfigOption) (*v1alpha1.Configuration, error) {
+	spec, err := ConfigurationSpec(pkgTest.ImagePath(names.Image))
+	if err != nil {
+		return nil, err
+	}
+
 	config := &v1alpha1.Configuration{
 		ObjectMeta: metav1.ObjectMeta{
 			Name: names.Config,
 		},
-		Spec: *ConfigurationSpec(pkgTest.ImagePath(names.Image)),
+		Spec: *spec,
 	}
 
 	for _, opt := range fopt {
 		opt(config)
 	}
 
-	return config
+	return config, nil
 }
 
 // WaitForConfigurationState polls the status of the Configuration called name
@@ -150,9 +176,106 @@ func WaitForConfigurationState(client *test.ServingAlphaClients, name string, in
 	return nil
 }
 
+// WatchConfigurationState is a watch-based alternative to
+// WaitForConfigurationState: rather than polling client.Configs.Get every
+// test.PollInterval, it seeds the current state with a single Get (so a
+// state reached before the watch starts isn't missed), then establishes a
+// watch on the named Configuration and dispatches every ADDED/MODIFIED event
+// through inState. It returns once inState returns `true`, returns an error,
+// ctx is done, or test.PollTimeout elapses. desc names the metric emitted to
+// track how long name took to reach the state checked by inState, matching
+// WaitForConfigurationState.
+//
+// Calling this more than once for the same Configuration (e.g. to wait on
+// it in separate phases, as WatchConfigLatestRevision does) re-emits
+// EventConfigurationCreated on every call unless a WithEventState carrying a
+// shared ConfigurationEventState is passed through each call.
+func WatchConfigurationState(ctx context.Context, client *test.ServingAlphaClients, name string, inState func(c *v1alpha1.Configuration) (bool, error), desc string, wopt ...WaitOption) error {
+	o := newWaitOptions(wopt...)
+	var emitter *configurationTransitionEmitter
+	if o.state != nil {
+		emitter = o.state.emitterFor(resolveEventSink(o))
+	} else {
+		emitter = newConfigurationTransitionEmitter(resolveEventSink(o))
+	}
+
+	span := logging.GetEmitableSpan(ctx, fmt.Sprintf("WatchConfigurationState/%s/%s", name, desc))
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, test.PollTimeout)
+	defer cancel()
+
+	var lastState *v1alpha1.Configuration
+	waitErr := watcher.Until(ctx,
+		func() (bool, error) {
+			c, err := client.Configs.Get(name, metav1.GetOptions{})
+			if err != nil {
+				return false, err
+			}
+			lastState = c
+			emitter.Observe(ctx, c)
+			return inState(c)
+		},
+		func() (watch.Interface, error) {
+			return client.Configs.Watch(metav1.ListOptions{
+				FieldSelector: fields.OneTermEqualSelector("metadata.name", name).String(),
+			})
+		},
+		func(obj interface{}) (bool, error) {
+			c, ok := obj.(*v1alpha1.Configuration)
+			if !ok {
+				return false, fmt.Errorf("unexpected watch object type %T for Configuration %q", obj, name)
+			}
+			lastState = c
+			emitter.Observe(ctx, c)
+			return inState(c)
+		},
+	)
+
+	if waitErr != nil {
+		return fmt.Errorf("configuration %q is not in desired state, got: %+v: %w", name, lastState, waitErr)
+	}
+	return nil
+}
+
+// WatchConfigLatestRevision is the watch-based counterpart to
+// WaitForConfigLatestRevision. Callers can switch to it independently of
+// WaitForConfigLatestRevision to cut down on Get traffic against a busy
+// apiserver without having to migrate every caller at once.
+func WatchConfigLatestRevision(ctx context.Context, clients *test.Clients, names test.ResourceNames, wopt ...WaitOption) (string, error) {
+	// Share one ConfigurationEventState across both phases below so
+	// EventConfigurationCreated fires once per Configuration, not once per
+	// phase; see WatchConfigurationState's doc comment.
+	wopt = append(wopt[:len(wopt):len(wopt)], WithEventState(NewConfigurationEventState()))
+
+	var revisionName string
+	err := WatchConfigurationState(ctx, clients.ServingAlphaClient, names.Config, func(c *v1alpha1.Configuration) (bool, error) {
+		if c.Status.LatestCreatedRevisionName != names.Revision {
+			revisionName = c.Status.LatestCreatedRevisionName
+			return true, nil
+		}
+		return false, nil
+	}, "ConfigurationUpdatedWithRevision", wopt...)
+	if err != nil {
+		return "", err
+	}
+	err = WatchConfigurationState(ctx, clients.ServingAlphaClient, names.Config, func(c *v1alpha1.Configuration) (bool, error) {
+		return (c.Status.LatestReadyRevisionName == revisionName), nil
+	}, "ConfigurationReadyWithRevision", wopt...)
+
+	return revisionName, err
+}
+
 // CheckConfigurationState verifies the status of the Configuration called name from client
 // is in a particular state by calling `inState` and expecting `true`.
-// This is the non-polling variety of WaitForConfigurationState
+// This is the non-polling variety of WaitForConfigurationState.
+//
+// CheckConfigurationState itself does a single Get and has no polling loop
+// of its own to convert to a watch, so there's nothing to change here. Test
+// code that wraps CheckConfigurationState in its own retry/poll loop to wait
+// for a state change should use WatchConfigurationState instead, which
+// applies the same watch-based pattern described on WatchConfigurationState
+// to that loop.
 func CheckConfigurationState(client *test.ServingAlphaClients, name string, inState func(r *v1alpha1.Configuration) (bool, error)) error {
 	c, err := client.Configs.Get(name, metav1.GetOptions{})
 	if err != nil {
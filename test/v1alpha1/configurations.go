@@ -0,0 +1,152 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// configurations.go provides helpers that operate on many Configurations at
+// once, fanning work out across a bounded worker pool instead of making
+// large e2e suites serialize dozens of individual round trips.
+
+package v1alpha1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"knative.dev/serving/pkg/apis/serving/v1alpha1"
+
+	pkgTest "knative.dev/pkg/test"
+	v1alpha1testing "knative.dev/serving/pkg/testing/v1alpha1"
+	"knative.dev/serving/test"
+)
+
+// DefaultConcurrency is the worker pool size CreateConfigurations falls back
+// to when concurrency <= 0, capped at the number of names.
+const DefaultConcurrency = 8
+
+// ConfigurationsError aggregates the per-Configuration failures produced by
+// CreateConfigurations, keyed by Configuration name. It implements Is/As so
+// callers can assert on an individual failure without knowing which name it
+// came from, e.g. `errors.As(err, &invalidImageErr)`.
+type ConfigurationsError struct {
+	Errors map[string]error
+}
+
+func (e *ConfigurationsError) Error() string {
+	return fmt.Sprintf("failed to create %d of the requested Configurations: %v", len(e.Errors), e.Errors)
+}
+
+// Is reports whether target matches any of the aggregated errors.
+func (e *ConfigurationsError) Is(target error) bool {
+	for _, err := range e.Errors {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As reports whether target matches any of the aggregated errors.
+func (e *ConfigurationsError) As(target interface{}) bool {
+	for _, err := range e.Errors {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// CreateConfigurations creates a Configuration for each entry in names,
+// fanning the creates out across a worker pool of the given concurrency
+// (concurrency <= 0 defaults to min(len(names), DefaultConcurrency)). The
+// returned slice preserves the order of names; an entry is nil if its
+// create failed. A non-nil error is always a *ConfigurationsError.
+func CreateConfigurations(t pkgTest.T, clients *test.Clients, names []test.ResourceNames, concurrency int, fopt ...v1alpha1testing.ConfigOption) ([]*v1alpha1.Configuration, error) {
+	if concurrency <= 0 {
+		concurrency = DefaultConcurrency
+	}
+	if concurrency > len(names) {
+		concurrency = len(names)
+	}
+	if concurrency == 0 {
+		return nil, nil
+	}
+
+	results := make([]*v1alpha1.Configuration, len(names))
+	errs := make([]error, len(names))
+
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, n := range names {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, n test.ResourceNames) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			cfg, err := CreateConfiguration(t, clients, n, fopt...)
+			if err != nil {
+				cfg = nil
+			}
+			results[i], errs[i] = cfg, err
+		}(i, n)
+	}
+	wg.Wait()
+
+	aggErr := &ConfigurationsError{Errors: map[string]error{}}
+	for i, err := range errs {
+		if err != nil {
+			aggErr.Errors[names[i].Config] = err
+		}
+	}
+	if len(aggErr.Errors) > 0 {
+		return results, aggErr
+	}
+	return results, nil
+}
+
+// WaitForConfigurationsState watches all of the named Configurations
+// concurrently, built on the same watch-based waiter as
+// WatchConfigurationState so that waiting on many Configurations at once
+// doesn't spawn a Get-polling loop per name. It returns once every
+// Configuration reaches the state checked by inState, returns as soon as
+// any one of them errors, or ctx is done.
+func WaitForConfigurationsState(ctx context.Context, client *test.ServingAlphaClients, names []string, inState func(c *v1alpha1.Configuration) (bool, error), desc string, wopt ...WaitOption) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	errCh := make(chan error, len(names))
+	var wg sync.WaitGroup
+	for _, name := range names {
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			if err := WatchConfigurationState(ctx, client, name, inState, desc, wopt...); err != nil {
+				select {
+				case errCh <- err:
+					cancel()
+				default:
+				}
+			}
+		}(name)
+	}
+	wg.Wait()
+	close(errCh)
+
+	for err := range errCh {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// CreateConfigurations and WaitForConfigurationsState fan their work out
+// against *test.Clients/*test.ServingAlphaClients, whose Configs field is a
+// concrete generated clientset type rather than an interface this package
+// can fake. What's unit-testable in isolation is ConfigurationsError, the
+// aggregation type CreateConfigurations reports its concurrent failures
+// through.
+
+func TestConfigurationsErrorIs(t *testing.T) {
+	wantErr := ErrEmptyImage
+	aggErr := &ConfigurationsError{Errors: map[string]error{
+		"config-a": errors.New("unrelated failure"),
+		"config-b": wantErr,
+	}}
+
+	if !errors.Is(aggErr, wantErr) {
+		t.Errorf("errors.Is(aggErr, wantErr) = false, want true")
+	}
+	if errors.Is(aggErr, errors.New("not aggregated")) {
+		t.Errorf("errors.Is(aggErr, unrelated) = true, want false")
+	}
+}
+
+func TestConfigurationsErrorAs(t *testing.T) {
+	aggErr := &ConfigurationsError{Errors: map[string]error{
+		"config-a": &InvalidImageError{Image: "not a valid image reference"},
+	}}
+
+	var invalidErr *InvalidImageError
+	if !errors.As(aggErr, &invalidErr) {
+		t.Fatalf("errors.As(aggErr, &invalidErr) = false, want true")
+	}
+	if invalidErr.Image != "not a valid image reference" {
+		t.Errorf("InvalidImageError.Image = %q, want %q", invalidErr.Image, "not a valid image reference")
+	}
+}
+
+func TestConfigurationsErrorEmpty(t *testing.T) {
+	aggErr := &ConfigurationsError{Errors: map[string]error{}}
+	if errors.Is(aggErr, ErrEmptyImage) {
+		t.Errorf("errors.Is(aggErr, ErrEmptyImage) = true, want false for an empty ConfigurationsError")
+	}
+}
+
+func TestConfigurationsErrorMessage(t *testing.T) {
+	aggErr := &ConfigurationsError{Errors: map[string]error{"config-a": errors.New("boom")}}
+	if got, want := aggErr.Error(), "failed to create 1 of the requested Configurations"; !strings.Contains(got, want) {
+		t.Fatalf("Error() = %q, want it to contain %q", got, want)
+	}
+}
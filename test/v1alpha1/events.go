@@ -0,0 +1,234 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// events.go lets WatchConfigurationState emit each Configuration state
+// transition it observes as a CloudEvent, mirroring the pattern
+// knative-gcp's Topic publisher uses to wire CloudEvents into reconciler
+// flows. This lets CI aggregate flake diagnostics across many parallel test
+// shards without scraping logs.
+
+package v1alpha1
+
+import (
+	"context"
+	"os"
+	"sync"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/google/uuid"
+
+	"knative.dev/serving/pkg/apis/serving/v1alpha1"
+)
+
+// ConfigurationEventsSinkEnvVar names the environment variable that, when
+// set, points WatchConfigurationState's default EventSink at a CloudEvents
+// HTTP receiver.
+const ConfigurationEventsSinkEnvVar = "CONFIGURATION_EVENTS_SINK"
+
+const (
+	// EventConfigurationCreated fires the first time WatchConfigurationState
+	// observes the Configuration.
+	EventConfigurationCreated = "dev.knative.serving.test.configuration.created"
+	// EventLatestCreatedRevisionChanged fires when Status.LatestCreatedRevisionName changes.
+	EventLatestCreatedRevisionChanged = "dev.knative.serving.test.configuration.latestcreatedrevision"
+	// EventLatestReadyRevisionChanged fires when Status.LatestReadyRevisionName changes.
+	EventLatestReadyRevisionChanged = "dev.knative.serving.test.configuration.latestreadyrevision"
+	// EventReadyConditionChanged fires when the Ready condition's status flips.
+	EventReadyConditionChanged = "dev.knative.serving.test.configuration.ready"
+)
+
+// EventSink is satisfied by cloudevents.Client (cloudevents.Result is
+// itself an alias for protocol.Result); it's the seam WatchConfigurationState
+// sends transition events through.
+type EventSink interface {
+	Send(ctx context.Context, event cloudevents.Event) cloudevents.Result
+}
+
+// noopEventSink drops every event. It's the default so existing callers of
+// WatchConfigurationState are unaffected.
+type noopEventSink struct{}
+
+func (noopEventSink) Send(context.Context, cloudevents.Event) cloudevents.Result { return nil }
+
+// DefaultEventSink is used by WatchConfigurationState when no sink is
+// configured via WithEventSink or ConfigurationEventsSinkEnvVar.
+var DefaultEventSink EventSink = noopEventSink{}
+
+// WaitOption customizes WatchConfigurationState.
+type WaitOption func(*waitOptions)
+
+type waitOptions struct {
+	sink  EventSink
+	state *ConfigurationEventState
+}
+
+// WithEventSink sends every Configuration state transition WatchConfigurationState
+// observes to sink as a CloudEvent, overriding ConfigurationEventsSinkEnvVar.
+func WithEventSink(sink EventSink) WaitOption {
+	return func(o *waitOptions) { o.sink = sink }
+}
+
+// WithEventState carries the CloudEvents emitter's last-observed
+// Configuration state across independent WatchConfigurationState calls.
+// Share one state across every call that watches the same Configuration
+// (e.g. WatchConfigLatestRevision's two phases) so EventConfigurationCreated
+// fires once per Configuration instead of once per call.
+func WithEventState(state *ConfigurationEventState) WaitOption {
+	return func(o *waitOptions) { o.state = state }
+}
+
+func newWaitOptions(wopt ...WaitOption) *waitOptions {
+	o := &waitOptions{}
+	for _, opt := range wopt {
+		opt(o)
+	}
+	return o
+}
+
+// ConfigurationEventState carries a configurationTransitionEmitter across
+// independent WatchConfigurationState calls for the same Configuration via
+// WithEventState. The zero value is ready to use; construct one with
+// NewConfigurationEventState and pass it to every call watching that
+// Configuration.
+type ConfigurationEventState struct {
+	mu      sync.Mutex
+	emitter *configurationTransitionEmitter
+}
+
+// NewConfigurationEventState returns a state to share across
+// WatchConfigurationState calls watching the same Configuration.
+func NewConfigurationEventState() *ConfigurationEventState {
+	return &ConfigurationEventState{}
+}
+
+// emitterFor returns the emitter to use for this call, creating it (with
+// sink) the first time and reusing it on every later call so the emitter's
+// last-observed state, and thus its de-duping of EventConfigurationCreated,
+// carries across calls. sink is only consulted the first time; later calls
+// keep using whichever sink the state was first created with.
+func (s *ConfigurationEventState) emitterFor(sink EventSink) *configurationTransitionEmitter {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.emitter == nil {
+		s.emitter = newConfigurationTransitionEmitter(sink)
+	}
+	return s.emitter
+}
+
+// resolveEventSink never fails: a misconfigured or unreachable
+// CONFIGURATION_EVENTS_SINK shouldn't take down the Configuration wait it's
+// only meant to observe, so any error building the CloudEvents client falls
+// back to DefaultEventSink.
+func resolveEventSink(o *waitOptions) EventSink {
+	if o.sink != nil {
+		return o.sink
+	}
+	target := os.Getenv(ConfigurationEventsSinkEnvVar)
+	if target == "" {
+		return DefaultEventSink
+	}
+	sink, err := cloudevents.NewClientHTTP(cloudevents.WithTarget(target))
+	if err != nil {
+		return DefaultEventSink
+	}
+	return sink
+}
+
+// configurationTransitionEmitter tracks the last-seen Configuration state
+// and emits a CloudEvent to sink for every transition WatchConfigurationState
+// observes. A single emitter must be shared across every
+// WatchConfigurationState phase watching the same Configuration (see
+// WatchConfigLatestRevision), or EventConfigurationCreated fires once per
+// phase instead of once per Configuration.
+type configurationTransitionEmitter struct {
+	sink          EventSink
+	seenFirst     bool
+	lastCreated   string
+	lastReady     string
+	lastReadyCond bool
+}
+
+func newConfigurationTransitionEmitter(sink EventSink) *configurationTransitionEmitter {
+	return &configurationTransitionEmitter{sink: sink}
+}
+
+// Observe compares c against the last-seen state and emits a CloudEvent for
+// each field that changed.
+func (e *configurationTransitionEmitter) Observe(ctx context.Context, c *v1alpha1.Configuration) {
+	readyCond := c.Status.IsReady()
+
+	if !e.seenFirst {
+		e.seenFirst = true
+		e.lastCreated = c.Status.LatestCreatedRevisionName
+		e.lastReady = c.Status.LatestReadyRevisionName
+		e.lastReadyCond = readyCond
+		e.emit(ctx, EventConfigurationCreated, c, nil)
+		return
+	}
+
+	if c.Status.LatestCreatedRevisionName != e.lastCreated {
+		e.emit(ctx, EventLatestCreatedRevisionChanged, c, map[string]interface{}{
+			"from": e.lastCreated,
+			"to":   c.Status.LatestCreatedRevisionName,
+		})
+		e.lastCreated = c.Status.LatestCreatedRevisionName
+	}
+	if c.Status.LatestReadyRevisionName != e.lastReady {
+		e.emit(ctx, EventLatestReadyRevisionChanged, c, map[string]interface{}{
+			"from": e.lastReady,
+			"to":   c.Status.LatestReadyRevisionName,
+		})
+		e.lastReady = c.Status.LatestReadyRevisionName
+	}
+	if readyCond != e.lastReadyCond {
+		e.emit(ctx, EventReadyConditionChanged, c, map[string]interface{}{
+			"from": e.lastReadyCond,
+			"to":   readyCond,
+		})
+		e.lastReadyCond = readyCond
+	}
+}
+
+// emit sends a CloudEvent carrying the Configuration's name, namespace, and
+// generation as attributes (per the request this implements), plus diff as
+// its data payload.
+func (e *configurationTransitionEmitter) emit(ctx context.Context, eventType string, c *v1alpha1.Configuration, diff map[string]interface{}) {
+	if _, ok := e.sink.(noopEventSink); ok {
+		return
+	}
+
+	event := cloudevents.NewEvent()
+	event.SetID(uuid.New().String())
+	event.SetType(eventType)
+	event.SetSource("knative.dev/serving/test/v1alpha1")
+	event.SetSubject(c.Name)
+	event.SetExtension("namespace", c.Namespace)
+	event.SetExtension("generation", c.Generation)
+	// SetData is given the map directly, not pre-marshaled bytes: passing a
+	// []byte payload stores it as data_base64 regardless of content type,
+	// which isn't the plain JSON data a consumer of this JSON-encoded event
+	// should see.
+	_ = event.SetData(cloudevents.ApplicationJSON, map[string]interface{}{
+		"name":       c.Name,
+		"namespace":  c.Namespace,
+		"generation": c.Generation,
+		"diff":       diff,
+	})
+
+	// Best effort: a test observer missing an event shouldn't fail the test
+	// itself, so errors here are intentionally swallowed.
+	_ = e.sink.Send(ctx, event)
+}
@@ -0,0 +1,160 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"knative.dev/serving/pkg/apis/serving/v1alpha1"
+)
+
+// fakeEventSink records every CloudEvent it's sent so tests can assert on
+// the transitions configurationTransitionEmitter observed.
+type fakeEventSink struct {
+	mu     sync.Mutex
+	events []cloudevents.Event
+}
+
+func (f *fakeEventSink) Send(_ context.Context, event cloudevents.Event) cloudevents.Result {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.events = append(f.events, event)
+	return nil
+}
+
+func (f *fakeEventSink) types() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var types []string
+	for _, e := range f.events {
+		types = append(types, e.Type())
+	}
+	return types
+}
+
+func newTestConfiguration() *v1alpha1.Configuration {
+	return &v1alpha1.Configuration{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "the-config",
+			Namespace:  "the-namespace",
+			Generation: 1,
+		},
+	}
+}
+
+func TestConfigurationTransitionEmitter(t *testing.T) {
+	sink := &fakeEventSink{}
+	emitter := newConfigurationTransitionEmitter(sink)
+	ctx := context.Background()
+
+	c := newTestConfiguration()
+	emitter.Observe(ctx, c)
+
+	c = c.DeepCopy()
+	c.Status.LatestCreatedRevisionName = "the-config-00001"
+	emitter.Observe(ctx, c)
+
+	c = c.DeepCopy()
+	c.Status.LatestReadyRevisionName = "the-config-00001"
+	emitter.Observe(ctx, c)
+
+	got := sink.types()
+	want := []string{
+		EventConfigurationCreated,
+		EventLatestCreatedRevisionChanged,
+		EventLatestReadyRevisionChanged,
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d events %v, want %d events %v", len(got), got, len(want), want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("event[%d] = %q, want %q", i, got[i], want[i])
+		}
+	}
+
+	for _, event := range sink.events {
+		if event.ID() == "" {
+			t.Errorf("event %q has no id, which CloudEvents 1.0 requires", event.Type())
+		}
+		if event.Subject() != c.Name {
+			t.Errorf("event %q subject = %q, want %q", event.Type(), event.Subject(), c.Name)
+		}
+		if got, want := event.Extensions()["namespace"], c.Namespace; got != want {
+			t.Errorf("event %q namespace extension = %v, want %v", event.Type(), got, want)
+		}
+		if event.Extensions()["generation"] == nil {
+			t.Errorf("event %q has no generation extension", event.Type())
+		}
+	}
+}
+
+// TestConfigurationTransitionEmitterSharedAcrossPhases guards against
+// EventConfigurationCreated firing once per WatchConfigurationState call
+// instead of once per Configuration: WatchConfigLatestRevision runs two
+// phases against the same emitter, and only the first should see the
+// Configuration as newly observed.
+func TestConfigurationTransitionEmitterSharedAcrossPhases(t *testing.T) {
+	sink := &fakeEventSink{}
+	emitter := newConfigurationTransitionEmitter(sink)
+	ctx := context.Background()
+
+	c := newTestConfiguration()
+	emitter.Observe(ctx, c) // phase one: first observation.
+	emitter.Observe(ctx, c) // phase two, same emitter: no change, no new event.
+
+	got := sink.types()
+	want := []string{EventConfigurationCreated}
+	if len(got) != len(want) {
+		t.Fatalf("got %d events %v, want %d events %v", len(got), got, len(want), want)
+	}
+}
+
+// TestConfigurationEventStateSharedAcrossCalls guards against
+// EventConfigurationCreated firing once per WatchConfigurationState call
+// instead of once per Configuration: two independent calls that resolve
+// their emitter through the same ConfigurationEventState (as WaitOption
+// plumbing does via WithEventState) must end up sharing the one emitter.
+func TestConfigurationEventStateSharedAcrossCalls(t *testing.T) {
+	sink := &fakeEventSink{}
+	state := NewConfigurationEventState()
+	ctx := context.Background()
+	c := newTestConfiguration()
+
+	// Simulates the per-call resolution WatchConfigurationState does when a
+	// WithEventState option is present.
+	state.emitterFor(sink).Observe(ctx, c)
+	state.emitterFor(sink).Observe(ctx, c)
+
+	got := sink.types()
+	want := []string{EventConfigurationCreated}
+	if len(got) != len(want) {
+		t.Fatalf("got %d events %v, want %d events %v", len(got), got, len(want), want)
+	}
+}
+
+func TestConfigurationTransitionEmitterNoopByDefault(t *testing.T) {
+	emitter := newConfigurationTransitionEmitter(DefaultEventSink)
+	// This only verifies Observe doesn't panic against the no-op sink; the
+	// no-op sink never records anything to assert on.
+	emitter.Observe(context.Background(), newTestConfiguration())
+}
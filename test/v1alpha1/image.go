@@ -0,0 +1,130 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// image.go validates the container image references handed to the
+// Configuration helpers so a malformed reference fails at construction time
+// instead of surfacing much later as a Revision pull failure.
+
+package v1alpha1
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+)
+
+// ErrEmptyImage is returned when an empty image reference is passed to a
+// Configuration helper.
+var ErrEmptyImage = errors.New("image reference must not be empty")
+
+// InvalidImageError reports that an image reference could not be parsed.
+type InvalidImageError struct {
+	Image string
+	Err   error
+}
+
+func (e *InvalidImageError) Error() string {
+	return fmt.Sprintf("image %q is not a valid reference: %v", e.Image, e.Err)
+}
+
+func (e *InvalidImageError) Unwrap() error { return e.Err }
+
+// AmbiguousImageError reports that an image reference specifies both a tag
+// and a digest, which this package rejects by default because it leaves
+// callers unable to tell which one the puller will honor.
+type AmbiguousImageError struct {
+	Image string
+}
+
+func (e *AmbiguousImageError) Error() string {
+	return fmt.Sprintf("image %q specifies both a tag and a digest; pass AllowTagAndDigest() if that's intended", e.Image)
+}
+
+// NoOpPatchError reports that PatchConfigImage was asked to patch a
+// Configuration's image to the canonical reference it already has, which
+// would never bump LatestCreatedRevisionName and so would hang
+// WaitForConfigLatestRevision until test.PollTimeout.
+type NoOpPatchError struct {
+	Image string
+}
+
+func (e *NoOpPatchError) Error() string {
+	return fmt.Sprintf("image %q is the same reference the Configuration already has; patch would be a no-op", e.Image)
+}
+
+// ImageOption customizes how normalizeImage validates an image reference.
+type ImageOption func(*imageOptions)
+
+type imageOptions struct {
+	strict            bool
+	allowTagAndDigest bool
+}
+
+// Strict requires the image reference to include a digest, so that an image
+// mutation between revisions is unambiguous.
+func Strict() ImageOption {
+	return func(o *imageOptions) { o.strict = true }
+}
+
+// AllowTagAndDigest permits a reference that specifies both a tag and a
+// digest, which normalizeImage otherwise rejects.
+func AllowTagAndDigest() ImageOption {
+	return func(o *imageOptions) { o.allowTagAndDigest = true }
+}
+
+// normalizeImage parses and validates imagePath with
+// github.com/google/go-containerregistry/pkg/name, returning a typed error
+// describing why the reference was rejected.
+func normalizeImage(imagePath string, iopt ...ImageOption) (name.Reference, error) {
+	if imagePath == "" {
+		return nil, ErrEmptyImage
+	}
+
+	o := &imageOptions{}
+	for _, opt := range iopt {
+		opt(o)
+	}
+
+	ref, err := name.ParseReference(imagePath, name.WeakValidation)
+	if err != nil {
+		return nil, &InvalidImageError{Image: imagePath, Err: err}
+	}
+
+	if _, isDigest := ref.(name.Digest); o.strict && !isDigest {
+		return nil, &InvalidImageError{Image: imagePath, Err: errors.New("Strict() requires a digest reference")}
+	}
+
+	if hasTagAndDigest(imagePath) && !o.allowTagAndDigest {
+		return nil, &AmbiguousImageError{Image: imagePath}
+	}
+
+	return ref, nil
+}
+
+// hasTagAndDigest reports whether imagePath specifies both a tag and a
+// digest, e.g. "gcr.io/foo/bar:latest@sha256:...". name.Reference doesn't
+// expose this directly since a digest reference subsumes the tag, so we
+// check the raw string.
+func hasTagAndDigest(imagePath string) bool {
+	atIdx := strings.LastIndex(imagePath, "@")
+	if atIdx == -1 {
+		return false
+	}
+	repo := imagePath[:atIdx]
+	return strings.Contains(repo[strings.LastIndex(repo, "/")+1:], ":")
+}
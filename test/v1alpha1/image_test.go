@@ -0,0 +1,89 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNormalizeImageEmpty(t *testing.T) {
+	if _, err := normalizeImage(""); !errors.Is(err, ErrEmptyImage) {
+		t.Errorf("normalizeImage(\"\") error = %v, want ErrEmptyImage", err)
+	}
+}
+
+func TestNormalizeImageInvalid(t *testing.T) {
+	_, err := normalizeImage("not a valid image reference")
+	var invalidErr *InvalidImageError
+	if !errors.As(err, &invalidErr) {
+		t.Fatalf("normalizeImage error = %v (%T), want *InvalidImageError", err, err)
+	}
+	if invalidErr.Image != "not a valid image reference" {
+		t.Errorf("InvalidImageError.Image = %q, want %q", invalidErr.Image, "not a valid image reference")
+	}
+}
+
+func TestNormalizeImageTagAndDigestAmbiguous(t *testing.T) {
+	const image = "gcr.io/foo/bar:latest@sha256:e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+	_, err := normalizeImage(image)
+	var ambiguousErr *AmbiguousImageError
+	if !errors.As(err, &ambiguousErr) {
+		t.Fatalf("normalizeImage(%q) error = %v (%T), want *AmbiguousImageError", image, err, err)
+	}
+
+	if _, err := normalizeImage(image, AllowTagAndDigest()); err != nil {
+		t.Errorf("normalizeImage(%q, AllowTagAndDigest()) = %v, want nil error", image, err)
+	}
+}
+
+func TestNormalizeImageDigestOnlyIsUnambiguous(t *testing.T) {
+	const image = "gcr.io/foo/bar@sha256:e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+	if _, err := normalizeImage(image); err != nil {
+		t.Errorf("normalizeImage(%q) = %v, want nil error", image, err)
+	}
+}
+
+func TestNormalizeImageStrictRequiresDigest(t *testing.T) {
+	const tagOnly = "gcr.io/foo/bar:latest"
+	const withDigest = "gcr.io/foo/bar@sha256:e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+	if _, err := normalizeImage(tagOnly, Strict()); err == nil {
+		t.Errorf("normalizeImage(%q, Strict()) = nil error, want an error since there's no digest", tagOnly)
+	}
+
+	if _, err := normalizeImage(withDigest, Strict()); err != nil {
+		t.Errorf("normalizeImage(%q, Strict()) = %v, want nil error", withDigest, err)
+	}
+}
+
+func TestHasTagAndDigest(t *testing.T) {
+	cases := map[string]bool{
+		"gcr.io/foo/bar:latest@sha256:abc":      true,
+		"gcr.io/foo/bar@sha256:abc":             false,
+		"gcr.io/foo/bar:latest":                 false,
+		"gcr.io:5000/foo/bar@sha256:abc":        false,
+		"gcr.io:5000/foo/bar:latest@sha256:abc": true,
+	}
+	for image, want := range cases {
+		if got := hasTagAndDigest(image); got != want {
+			t.Errorf("hasTagAndDigest(%q) = %v, want %v", image, got, want)
+		}
+	}
+}
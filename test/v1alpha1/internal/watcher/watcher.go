@@ -0,0 +1,67 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package watcher holds the watch.Interface plumbing shared by the
+// WatchXState helpers in test/v1alpha1 (and, eventually, test/v1): seed the
+// current state with a Get, then dispatch ADDED/MODIFIED watch events
+// through a predicate until it is satisfied, the context is done, or the
+// watch closes.
+package watcher
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// Until seeds the desired state with seed (a plain Get), and if that didn't
+// already satisfy the caller, starts a watch via startWatch and feeds every
+// ADDED/MODIFIED event to accept until accept returns true, ctx is done, or
+// the watch channel closes. seed lets callers avoid missing a state that was
+// already reached before the watch was established.
+func Until(ctx context.Context, seed func() (bool, error), startWatch func() (watch.Interface, error), accept func(obj interface{}) (bool, error)) error {
+	if done, err := seed(); err != nil || done {
+		return err
+	}
+
+	w, err := startWatch()
+	if err != nil {
+		return err
+	}
+	defer w.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case event, ok := <-w.ResultChan():
+			if !ok {
+				return fmt.Errorf("watch closed before the desired state was reached")
+			}
+			switch event.Type {
+			case watch.Added, watch.Modified:
+				if done, err := accept(event.Object); err != nil || done {
+					return err
+				}
+			case watch.Deleted:
+				return fmt.Errorf("watched object was deleted before the desired state was reached")
+			case watch.Error:
+				return fmt.Errorf("watch error before the desired state was reached: %+v", event.Object)
+			}
+		}
+	}
+}
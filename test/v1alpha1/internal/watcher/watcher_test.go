@@ -0,0 +1,189 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package watcher
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+func pod(name string) *corev1.Pod {
+	return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name}}
+}
+
+// neverWatch fails the test if Until calls startWatch at all, for cases
+// where seed is expected to already satisfy the caller.
+func neverWatch(t *testing.T) func() (watch.Interface, error) {
+	return func() (watch.Interface, error) {
+		t.Fatal("startWatch called despite seed already satisfying accept")
+		return nil, nil
+	}
+}
+
+func TestUntilSeedAlreadySatisfied(t *testing.T) {
+	err := Until(context.Background(),
+		func() (bool, error) { return true, nil },
+		neverWatch(t),
+		func(interface{}) (bool, error) {
+			t.Fatal("accept called despite seed already being done")
+			return false, nil
+		},
+	)
+	if err != nil {
+		t.Fatalf("Until() = %v, want nil", err)
+	}
+}
+
+func TestUntilSeedError(t *testing.T) {
+	wantErr := errors.New("boom")
+	err := Until(context.Background(),
+		func() (bool, error) { return false, wantErr },
+		neverWatch(t),
+		func(interface{}) (bool, error) { return false, nil },
+	)
+	if !errors.Is(err, wantErr) {
+		t.Fatalf("Until() = %v, want %v", err, wantErr)
+	}
+}
+
+// TestUntilWatchEventSatisfies checks that accept is fed ADDED/MODIFIED
+// events until one of them is satisfied, ignoring events that aren't.
+func TestUntilWatchEventSatisfies(t *testing.T) {
+	fw := watch.NewFake()
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- Until(context.Background(),
+			func() (bool, error) { return false, nil },
+			func() (watch.Interface, error) { return fw, nil },
+			func(obj interface{}) (bool, error) {
+				return obj.(*corev1.Pod).Name == "done", nil
+			},
+		)
+	}()
+
+	fw.Add(pod("not-yet"))
+	fw.Modify(pod("done"))
+
+	select {
+	case err := <-errCh:
+		if err != nil {
+			t.Fatalf("Until() = %v, want nil", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Until() did not return after accept was satisfied")
+	}
+}
+
+func TestUntilContextDone(t *testing.T) {
+	fw := watch.NewFake()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- Until(ctx,
+			func() (bool, error) { return false, nil },
+			func() (watch.Interface, error) { return fw, nil },
+			func(interface{}) (bool, error) { return false, nil },
+		)
+	}()
+
+	cancel()
+
+	select {
+	case err := <-errCh:
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("Until() = %v, want context.Canceled", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Until() did not return after ctx was done")
+	}
+}
+
+func TestUntilWatchClosed(t *testing.T) {
+	fw := watch.NewFake()
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- Until(context.Background(),
+			func() (bool, error) { return false, nil },
+			func() (watch.Interface, error) { return fw, nil },
+			func(interface{}) (bool, error) { return false, nil },
+		)
+	}()
+
+	fw.Stop()
+
+	select {
+	case err := <-errCh:
+		if err == nil {
+			t.Fatal("Until() = nil, want an error for the closed watch")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Until() did not return after the watch was closed")
+	}
+}
+
+func TestUntilDeleted(t *testing.T) {
+	fw := watch.NewFake()
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- Until(context.Background(),
+			func() (bool, error) { return false, nil },
+			func() (watch.Interface, error) { return fw, nil },
+			func(interface{}) (bool, error) { return false, nil },
+		)
+	}()
+
+	fw.Delete(pod("the-pod"))
+
+	select {
+	case err := <-errCh:
+		if err == nil {
+			t.Fatal("Until() = nil, want an error for the Deleted event")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Until() did not return after a Deleted event")
+	}
+}
+
+func TestUntilError(t *testing.T) {
+	fw := watch.NewFake()
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- Until(context.Background(),
+			func() (bool, error) { return false, nil },
+			func() (watch.Interface, error) { return fw, nil },
+			func(interface{}) (bool, error) { return false, nil },
+		)
+	}()
+
+	fw.Error(&metav1.Status{Message: "synthetic watch error"})
+
+	select {
+	case err := <-errCh:
+		if err == nil {
+			t.Fatal("Until() = nil, want an error for the Error event")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Until() did not return after an Error event")
+	}
+}